@@ -0,0 +1,50 @@
+package yqlib
+
+import (
+	"io"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// cborEncoder walks a CandidateNode tree (via MarshalYAML, same as the other
+// encoders) and emits a CBOR-encoded document. It's binary, so there's no
+// separator between documents or leading comments/front-matter to preserve -
+// PrintResults frames multi-document output instead.
+type cborEncoder struct {
+}
+
+func NewCborEncoder() Encoder {
+	return &cborEncoder{}
+}
+
+func (ce *cborEncoder) CanHandleAliases() bool {
+	return false
+}
+
+func (ce *cborEncoder) PrintDocumentSeparator(writer io.Writer) error {
+	return nil
+}
+
+func (ce *cborEncoder) PrintLeadingContent(writer io.Writer, content string) error {
+	return nil
+}
+
+func (ce *cborEncoder) Encode(writer io.Writer, node *CandidateNode) error {
+	target, err := node.MarshalYAML()
+	if err != nil {
+		return err
+	}
+
+	var dataBucket interface{}
+	if err := target.Decode(&dataBucket); err != nil {
+		return err
+	}
+
+	encoded, err := cbor.Marshal(dataBucket)
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(encoded)
+	return err
+}
@@ -0,0 +1,55 @@
+//go:build !windows
+// +build !windows
+
+package yqlib
+
+import (
+	"fmt"
+	"path/filepath"
+	"plugin"
+)
+
+// LoadFormatPlugin loads a single Go plugin (.so) built with `go build
+// -buildmode=plugin` and registers the format(s) it provides. A plugin
+// registers itself either by calling RegisterFormat from its own init()
+// (triggered automatically by plugin.Open), or by exporting a
+// `YqlibFormatPlugins []*PrinterOutputFormat` symbol that this loader appends
+// directly - useful when the plugin can't depend on yqlib's package-level
+// init ordering.
+func LoadFormatPlugin(path string) error {
+	loadedPlugin, err := plugin.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to load format plugin %q: %w", path, err)
+	}
+
+	sym, err := loadedPlugin.Lookup("YqlibFormatPlugins")
+	if err != nil {
+		// no explicit export - assume the plugin registered itself via init()
+		return nil
+	}
+
+	formats, ok := sym.(*[]*PrinterOutputFormat)
+	if !ok {
+		return fmt.Errorf("format plugin %q exported YqlibFormatPlugins with an unexpected type", path)
+	}
+
+	Formats = append(Formats, *formats...)
+	return nil
+}
+
+// LoadFormatPluginsFromDir loads every *.so file found directly in dir as a
+// format plugin. This lets users drop in codecs (e.g. HCL, Avro, MessagePack)
+// without patching yq itself.
+func LoadFormatPluginsFromDir(dir string) error {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.so"))
+	if err != nil {
+		return fmt.Errorf("failed to scan %q for format plugins: %w", dir, err)
+	}
+
+	for _, match := range matches {
+		if err := LoadFormatPlugin(match); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+//go:build windows
+// +build windows
+
+package yqlib
+
+import "fmt"
+
+// LoadFormatPlugin is unavailable on windows: the Go plugin package only
+// supports linux, darwin and freebsd.
+func LoadFormatPlugin(path string) error {
+	return fmt.Errorf("format plugins are not supported on windows")
+}
+
+// LoadFormatPluginsFromDir is unavailable on windows for the same reason as
+// LoadFormatPlugin.
+func LoadFormatPluginsFromDir(dir string) error {
+	return fmt.Errorf("format plugins are not supported on windows")
+}
@@ -0,0 +1,50 @@
+package yqlib
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// FramingMode controls how resultsPrinter delimits encoded documents when
+// writing to a single stream, for embedding yq in pipelines that need a
+// binary-safe alternative to NUL separation.
+type FramingMode uint32
+
+const (
+	NoFraming FramingMode = iota
+	// NetstringFraming wraps each document as `<len>:<payload>,` (djb netstrings).
+	NetstringFraming
+	// LengthPrefixFraming prefixes each document with its length as a 4 byte
+	// big-endian unsigned integer.
+	LengthPrefixFraming
+)
+
+func FramingModeFromString(mode string) (FramingMode, error) {
+	switch mode {
+	case "":
+		return NoFraming, nil
+	case "netstring":
+		return NetstringFraming, nil
+	case "length-prefix":
+		return LengthPrefixFraming, nil
+	default:
+		return NoFraming, fmt.Errorf("unknown framing mode '%v' please use [netstring|length-prefix]", mode)
+	}
+}
+
+// frameBytes wraps data according to mode. NoFraming returns data unchanged.
+func frameBytes(mode FramingMode, data []byte) ([]byte, error) {
+	switch mode {
+	case NoFraming:
+		return data, nil
+	case NetstringFraming:
+		return []byte(fmt.Sprintf("%d:%s,", len(data), data)), nil
+	case LengthPrefixFraming:
+		framed := make([]byte, 4+len(data))
+		binary.BigEndian.PutUint32(framed, uint32(len(data)))
+		copy(framed[4:], data)
+		return framed, nil
+	default:
+		return nil, fmt.Errorf("unknown framing mode '%v'", mode)
+	}
+}
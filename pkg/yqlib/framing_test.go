@@ -0,0 +1,55 @@
+package yqlib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrameBytesNoFraming(t *testing.T) {
+	framed, err := frameBytes(NoFraming, []byte("hello"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("hello"), framed)
+}
+
+func TestFrameBytesNetstring(t *testing.T) {
+	framed, err := frameBytes(NetstringFraming, []byte("hello"))
+	assert.NoError(t, err)
+	assert.Equal(t, "5:hello,", string(framed))
+}
+
+func TestFrameBytesNetstringEmptyPayload(t *testing.T) {
+	framed, err := frameBytes(NetstringFraming, []byte{})
+	assert.NoError(t, err)
+	assert.Equal(t, "0:,", string(framed))
+}
+
+func TestFrameBytesLengthPrefix(t *testing.T) {
+	framed, err := frameBytes(LengthPrefixFraming, []byte("hello"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x00, 0x00, 0x00, 0x05, 'h', 'e', 'l', 'l', 'o'}, framed)
+}
+
+func TestFrameBytesLengthPrefixPreservesTrailingControlBytes(t *testing.T) {
+	payload := []byte{0x01, 0x0a}
+	framed, err := frameBytes(LengthPrefixFraming, payload)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x00, 0x00, 0x00, 0x02, 0x01, 0x0a}, framed)
+}
+
+func TestFramingModeFromString(t *testing.T) {
+	mode, err := FramingModeFromString("netstring")
+	assert.NoError(t, err)
+	assert.Equal(t, NetstringFraming, mode)
+
+	mode, err = FramingModeFromString("length-prefix")
+	assert.NoError(t, err)
+	assert.Equal(t, LengthPrefixFraming, mode)
+
+	mode, err = FramingModeFromString("")
+	assert.NoError(t, err)
+	assert.Equal(t, NoFraming, mode)
+
+	_, err = FramingModeFromString("bogus")
+	assert.Error(t, err)
+}
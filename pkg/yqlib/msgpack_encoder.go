@@ -0,0 +1,50 @@
+package yqlib
+
+import (
+	"io"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// msgpackEncoder walks a CandidateNode tree (via MarshalYAML, same as the
+// other encoders) and emits a MessagePack-encoded document. It's binary, so
+// there's no separator between documents or leading comments/front-matter to
+// preserve - PrintResults frames multi-document output instead.
+type msgpackEncoder struct {
+}
+
+func NewMsgpackEncoder() Encoder {
+	return &msgpackEncoder{}
+}
+
+func (me *msgpackEncoder) CanHandleAliases() bool {
+	return false
+}
+
+func (me *msgpackEncoder) PrintDocumentSeparator(writer io.Writer) error {
+	return nil
+}
+
+func (me *msgpackEncoder) PrintLeadingContent(writer io.Writer, content string) error {
+	return nil
+}
+
+func (me *msgpackEncoder) Encode(writer io.Writer, node *CandidateNode) error {
+	target, err := node.MarshalYAML()
+	if err != nil {
+		return err
+	}
+
+	var dataBucket interface{}
+	if err := target.Decode(&dataBucket); err != nil {
+		return err
+	}
+
+	encoded, err := msgpack.Marshal(dataBucket)
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(encoded)
+	return err
+}
@@ -0,0 +1,35 @@
+package yqlib
+
+import "io"
+
+// ndjsonEncoder wraps the JSON encoder to produce newline-delimited JSON:
+// one compact document per line, with no `---`-style document separators.
+// This keeps the output safe to pipe into log processors and `jq -c`
+// consumers.
+type ndjsonEncoder struct {
+	jsonEncoder Encoder
+}
+
+func NewNDJSONEncoder() Encoder {
+	prefs := ConfiguredJSONPreferences
+	prefs.Indent = 0
+	prefs.UnwrapScalar = false
+	return &ndjsonEncoder{jsonEncoder: NewJSONEncoder(prefs)}
+}
+
+func (ne *ndjsonEncoder) CanHandleAliases() bool {
+	return ne.jsonEncoder.CanHandleAliases()
+}
+
+func (ne *ndjsonEncoder) PrintDocumentSeparator(writer io.Writer) error {
+	// ndjson has no document separators - each line is its own document.
+	return nil
+}
+
+func (ne *ndjsonEncoder) PrintLeadingContent(writer io.Writer, content string) error {
+	return ne.jsonEncoder.PrintLeadingContent(writer, content)
+}
+
+func (ne *ndjsonEncoder) Encode(writer io.Writer, node *CandidateNode) error {
+	return ne.jsonEncoder.Encode(writer, node)
+}
@@ -0,0 +1,29 @@
+package yqlib
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNdjsonEncoderPrintDocumentSeparatorIsNoop(t *testing.T) {
+	encoder := &ndjsonEncoder{jsonEncoder: &fakeEncoder{}}
+	var buf bytes.Buffer
+
+	err := encoder.PrintDocumentSeparator(&buf)
+
+	assert.NoError(t, err)
+	assert.Empty(t, buf.String())
+}
+
+func TestNdjsonEncoderDelegatesEncodeToJSONEncoder(t *testing.T) {
+	underlying := &fakeEncoder{payloads: map[string][]byte{"a": []byte(`{"a":1}`)}}
+	encoder := &ndjsonEncoder{jsonEncoder: underlying}
+	var buf bytes.Buffer
+
+	err := encoder.Encode(&buf, &CandidateNode{Value: "a"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, buf.String())
+}
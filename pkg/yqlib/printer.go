@@ -8,6 +8,7 @@ import (
 	"io"
 	"regexp"
 	"strings"
+	"sync"
 )
 
 type Printer interface {
@@ -16,47 +17,134 @@ type Printer interface {
 	//e.g. when given a front-matter doc, like jekyll
 	SetAppendix(reader io.Reader)
 	SetNulSepOutput(nulSepOutput bool)
+	// SetFraming wraps each printed document in netstring or length-prefix
+	// framing instead of (or as well as) NUL separation, for binary-safe
+	// pipelines where payloads may legitimately contain NUL bytes.
+	SetFraming(mode FramingMode)
+	// SetFormatCapabilities tells the printer what its output format supports,
+	// e.g. whether aliases need to be exploded before printing.
+	SetFormatCapabilities(capabilities FormatCapabilities)
+	// SetEncoderFactory lets the printer build one Encoder instance per
+	// worker when concurrency is enabled with SetConcurrency(n) and n > 1.
+	SetEncoderFactory(factory EncoderFactoryFunction)
+	// SetConcurrency enables a worker pool that encodes documents in
+	// parallel when n > 1, each worker owning its own Encoder from the
+	// factory set via SetEncoderFactory. Default is 1, preserving the
+	// original single-goroutine behaviour.
+	SetConcurrency(n int)
 }
 
 type EncoderFactoryFunction func() Encoder
+type DecoderFactoryFunction func() Decoder
+
+// FormatCapabilities describes the optional behaviours a registered format
+// supports, so callers (e.g. PrintResults, the CLI completion list) can make
+// decisions without type-switching on the format itself.
+type FormatCapabilities struct {
+	Streaming  bool // can be written one document at a time with no whole-stream buffering
+	Comments   bool // round-trips comments
+	Aliases    bool // can represent YAML aliases/anchors without needing them exploded first
+	SingleLine bool // each encoded document is guaranteed to be exactly one line, e.g. ndjson
+	Binary     bool // binary encoded; has no textual document separator, so multi-document output must be framed
+}
 
 type PrinterOutputFormat struct {
 	FormalName     string
 	Names          []string
 	EncoderFactory EncoderFactoryFunction
+	DecoderFactory DecoderFactoryFunction
+	Capabilities   FormatCapabilities
 }
 
-var YamlOutputFormat = &PrinterOutputFormat{"yaml", []string{"y", "yml"}, func() Encoder { return NewYamlEncoder(ConfiguredYamlPreferences) }}
-var JSONOutputFormat = &PrinterOutputFormat{"json", []string{"j"}, func() Encoder { return NewJSONEncoder(ConfiguredJSONPreferences) }}
-var PropsOutputFormat = &PrinterOutputFormat{"props", []string{"p", "properties"}, func() Encoder { return NewPropertiesEncoder(ConfiguredPropertiesPreferences) }}
-var CSVOutputFormat = &PrinterOutputFormat{"csv", []string{"c"}, func() Encoder { return NewCsvEncoder(ConfiguredCsvPreferences) }}
-var TSVOutputFormat = &PrinterOutputFormat{"tsv", []string{"t"}, func() Encoder { return NewCsvEncoder(ConfiguredTsvPreferences) }}
-var XMLOutputFormat = &PrinterOutputFormat{"xml", []string{"x"}, func() Encoder { return NewXMLEncoder(ConfiguredXMLPreferences) }}
-
-var Base64OutputFormat = &PrinterOutputFormat{}
-var UriOutputFormat = &PrinterOutputFormat{}
-var ShOutputFormat = &PrinterOutputFormat{}
-
-var TomlOutputFormat = &PrinterOutputFormat{"toml", []string{}, func() Encoder { return NewTomlEncoder() }}
-var ShellVariablesOutputFormat = &PrinterOutputFormat{"shell", []string{"s", "sh"}, func() Encoder { return NewShellVariablesEncoder() }}
-
-var LuaOutputFormat = &PrinterOutputFormat{"lua", []string{"l"}, func() Encoder { return NewLuaEncoder(ConfiguredLuaPreferences) }}
-
-var Formats = []*PrinterOutputFormat{
-	YamlOutputFormat,
-	JSONOutputFormat,
-	PropsOutputFormat,
-	CSVOutputFormat,
-	TSVOutputFormat,
-	XMLOutputFormat,
-	Base64OutputFormat,
-	UriOutputFormat,
-	ShOutputFormat,
-	TomlOutputFormat,
-	ShellVariablesOutputFormat,
-	LuaOutputFormat,
+// Formats is the registry of known output formats. It starts out populated
+// with yq's built-in formats; RegisterFormat appends to it so that external
+// codecs (plugins or formats registered via init()) are seen uniformly by
+// OutputFormatFromString, the CLI completion list and operator_encoder_decoder.
+var Formats = []*PrinterOutputFormat{}
+
+// RegisterFormat adds a format to the registry, pairing an encoder factory
+// with an optional decoder factory under a formal name and its aliases.
+// Plugins loaded via LoadFormatPlugin, as well as this package's own init,
+// call this to make a format visible everywhere formats are looked up.
+func RegisterFormat(name string, aliases []string, encoderFactory EncoderFactoryFunction, decoderFactory DecoderFactoryFunction, capabilities FormatCapabilities) *PrinterOutputFormat {
+	format := &PrinterOutputFormat{
+		FormalName:     name,
+		Names:          aliases,
+		EncoderFactory: encoderFactory,
+		DecoderFactory: decoderFactory,
+		Capabilities:   capabilities,
+	}
+	Formats = append(Formats, format)
+	return format
 }
 
+var YamlOutputFormat = RegisterFormat("yaml", []string{"y", "yml"},
+	func() Encoder { return NewYamlEncoder(ConfiguredYamlPreferences) },
+	func() Decoder { return NewYamlDecoder(ConfiguredYamlPreferences) },
+	FormatCapabilities{Streaming: true, Comments: true, Aliases: true})
+
+var JSONOutputFormat = RegisterFormat("json", []string{"j"},
+	func() Encoder { return NewJSONEncoder(ConfiguredJSONPreferences) },
+	func() Decoder { return NewJSONDecoder() },
+	FormatCapabilities{Streaming: true})
+
+var NDJSONOutputFormat = RegisterFormat("ndjson", []string{"jsonl"},
+	func() Encoder { return NewNDJSONEncoder() },
+	func() Decoder { return NewJSONDecoder() },
+	FormatCapabilities{Streaming: true, SingleLine: true})
+
+var PropsOutputFormat = RegisterFormat("props", []string{"p", "properties"},
+	func() Encoder { return NewPropertiesEncoder(ConfiguredPropertiesPreferences) },
+	func() Decoder { return NewPropertiesDecoder() },
+	FormatCapabilities{Comments: true})
+
+var CSVOutputFormat = RegisterFormat("csv", []string{"c"},
+	func() Encoder { return NewCsvEncoder(ConfiguredCsvPreferences) },
+	func() Decoder { return NewCSVObjectDecoder(ConfiguredCsvPreferences) },
+	FormatCapabilities{})
+
+var TSVOutputFormat = RegisterFormat("tsv", []string{"t"},
+	func() Encoder { return NewCsvEncoder(ConfiguredTsvPreferences) },
+	func() Decoder { return NewCSVObjectDecoder(ConfiguredTsvPreferences) },
+	FormatCapabilities{})
+
+var XMLOutputFormat = RegisterFormat("xml", []string{"x"},
+	func() Encoder { return NewXMLEncoder(ConfiguredXMLPreferences) },
+	func() Decoder { return NewXMLDecoder(ConfiguredXMLPreferences) },
+	FormatCapabilities{Streaming: true})
+
+// Base64OutputFormat, UriOutputFormat and ShOutputFormat are deliberately
+// anonymous (no FormalName/Names): they're not selectable via -o, only used
+// directly by operator_encoder_decoder for the @base64/@uri/@sh operators.
+var Base64OutputFormat = RegisterFormat("", []string{}, nil, nil, FormatCapabilities{})
+var UriOutputFormat = RegisterFormat("", []string{}, nil, nil, FormatCapabilities{})
+var ShOutputFormat = RegisterFormat("", []string{}, nil, nil, FormatCapabilities{})
+
+var TomlOutputFormat = RegisterFormat("toml", []string{},
+	func() Encoder { return NewTomlEncoder() },
+	nil,
+	FormatCapabilities{})
+
+var ShellVariablesOutputFormat = RegisterFormat("shell", []string{"s", "sh"},
+	func() Encoder { return NewShellVariablesEncoder() },
+	nil,
+	FormatCapabilities{})
+
+var LuaOutputFormat = RegisterFormat("lua", []string{"l"},
+	func() Encoder { return NewLuaEncoder(ConfiguredLuaPreferences) },
+	nil,
+	FormatCapabilities{Streaming: true})
+
+var CborOutputFormat = RegisterFormat("cbor", []string{},
+	func() Encoder { return NewCborEncoder() },
+	nil,
+	FormatCapabilities{Binary: true})
+
+var MsgpackOutputFormat = RegisterFormat("msgpack", []string{},
+	func() Encoder { return NewMsgpackEncoder() },
+	nil,
+	FormatCapabilities{Binary: true})
+
 func (f *PrinterOutputFormat) MatchesName(name string) bool {
 	if f.FormalName == name {
 		return true
@@ -73,6 +161,15 @@ func (f *PrinterOutputFormat) GetConfiguredEncoder() Encoder {
 	return f.EncoderFactory()
 }
 
+// GetConfiguredDecoder returns a Decoder for this format, or nil if the
+// format is encode-only (e.g. lua, shell).
+func (f *PrinterOutputFormat) GetConfiguredDecoder() Decoder {
+	if f.DecoderFactory == nil {
+		return nil
+	}
+	return f.DecoderFactory()
+}
+
 func OutputFormatFromString(format string) (*PrinterOutputFormat, error) {
 	for _, printerFormat := range Formats {
 		if printerFormat.MatchesName(format) {
@@ -97,15 +194,19 @@ func GetAvailableOutputFormatString() string {
 }
 
 type resultsPrinter struct {
-	encoder           Encoder
-	printerWriter     PrinterWriter
-	firstTimePrinting bool
-	previousDocIndex  uint
-	previousFileIndex int
-	printedMatches    bool
-	treeNavigator     DataTreeNavigator
-	appendixReader    io.Reader
-	nulSepOutput      bool
+	encoder            Encoder
+	printerWriter      PrinterWriter
+	firstTimePrinting  bool
+	previousDocIndex   uint
+	previousFileIndex  int
+	printedMatches     bool
+	treeNavigator      DataTreeNavigator
+	appendixReader     io.Reader
+	nulSepOutput       bool
+	framing            FramingMode
+	formatCapabilities *FormatCapabilities
+	encoderFactory     EncoderFactoryFunction
+	concurrency        int
 }
 
 func NewPrinter(encoder Encoder, printerWriter PrinterWriter) Printer {
@@ -115,6 +216,7 @@ func NewPrinter(encoder Encoder, printerWriter PrinterWriter) Printer {
 		firstTimePrinting: true,
 		treeNavigator:     NewDataTreeNavigator(),
 		nulSepOutput:      false,
+		concurrency:       1,
 	}
 }
 
@@ -124,10 +226,36 @@ func (p *resultsPrinter) SetNulSepOutput(nulSepOutput bool) {
 	p.nulSepOutput = nulSepOutput
 }
 
+func (p *resultsPrinter) SetFraming(mode FramingMode) {
+	log.Debug("Setting framing mode to %v", mode)
+
+	p.framing = mode
+}
+
+func (p *resultsPrinter) SetEncoderFactory(factory EncoderFactoryFunction) {
+	p.encoderFactory = factory
+}
+
+func (p *resultsPrinter) SetConcurrency(n int) {
+	if n < 1 {
+		n = 1
+	}
+	log.Debug("Setting printer concurrency to %v", n)
+
+	p.concurrency = n
+}
+
 func (p *resultsPrinter) SetAppendix(reader io.Reader) {
 	p.appendixReader = reader
 }
 
+// SetFormatCapabilities tells the printer what the configured encoder's
+// output format can do, e.g. whether it can round-trip aliases. When unset,
+// PrintResults falls back to encoder.CanHandleAliases().
+func (p *resultsPrinter) SetFormatCapabilities(capabilities FormatCapabilities) {
+	p.formatCapabilities = &capabilities
+}
+
 func (p *resultsPrinter) PrintedAnything() bool {
 	return p.printedMatches
 }
@@ -156,7 +284,12 @@ func (p *resultsPrinter) PrintResults(matchingNodes *list.List) error {
 		return nil
 	}
 
-	if !p.encoder.CanHandleAliases() {
+	supportsAliases := p.encoder.CanHandleAliases()
+	if p.formatCapabilities != nil {
+		supportsAliases = p.formatCapabilities.Aliases
+	}
+
+	if !supportsAliases {
 		explodeOp := Operation{OperationType: explodeOpType}
 		explodeNode := ExpressionNode{Operation: &explodeOp}
 		context, err := p.treeNavigator.GetMatchingNodes(Context{MatchingNodes: matchingNodes}, &explodeNode)
@@ -166,6 +299,16 @@ func (p *resultsPrinter) PrintResults(matchingNodes *list.List) error {
 		matchingNodes = context.MatchingNodes
 	}
 
+	// Binary formats have no textual document separator, so when more than one
+	// document is going to be printed we must frame them (defaulting to
+	// length-prefix) unless the caller already asked for NUL separation or a
+	// specific framing mode.
+	effectiveFraming := p.framing
+	binaryFormat := p.formatCapabilities != nil && p.formatCapabilities.Binary
+	if binaryFormat && effectiveFraming == NoFraming && !p.nulSepOutput && matchingNodes.Len() > 1 {
+		effectiveFraming = LengthPrefixFraming
+	}
+
 	if p.firstTimePrinting {
 		node := matchingNodes.Front().Value.(*CandidateNode)
 		p.previousDocIndex = node.GetDocument()
@@ -173,6 +316,13 @@ func (p *resultsPrinter) PrintResults(matchingNodes *list.List) error {
 		p.firstTimePrinting = false
 	}
 
+	if p.concurrency > 1 && p.encoderFactory != nil && matchingNodes.Len() > 1 {
+		if err := p.printResultsConcurrently(matchingNodes, effectiveFraming); err != nil {
+			return err
+		}
+		return p.printAppendix()
+	}
+
 	for el := matchingNodes.Front(); el != nil; el = el.Next() {
 
 		mappedDoc := el.Value.(*CandidateNode)
@@ -186,15 +336,19 @@ func (p *resultsPrinter) PrintResults(matchingNodes *list.List) error {
 		commentsStartWithSepExp := regexp.MustCompile(`^\$yqDocSeparator\$`)
 		commentStartsWithSeparator := commentsStartWithSepExp.MatchString(mappedDoc.LeadingContent)
 
-		if (p.previousDocIndex != mappedDoc.GetDocument() || p.previousFileIndex != mappedDoc.GetFileIndex()) && !commentStartsWithSeparator {
+		singleLineFormat := p.formatCapabilities != nil && p.formatCapabilities.SingleLine
+		skipSeparator := singleLineFormat || binaryFormat
+		if (p.previousDocIndex != mappedDoc.GetDocument() || p.previousFileIndex != mappedDoc.GetFileIndex()) && !commentStartsWithSeparator && !skipSeparator {
 			if err := p.encoder.PrintDocumentSeparator(writer); err != nil {
 				return err
 			}
 		}
 
+		buffering := p.nulSepOutput || effectiveFraming != NoFraming
+
 		var destination io.Writer = writer
 		tempBuffer := bytes.NewBuffer(nil)
-		if p.nulSepOutput {
+		if buffering {
 			destination = tempBuffer
 		}
 
@@ -206,29 +360,60 @@ func (p *resultsPrinter) PrintResults(matchingNodes *list.List) error {
 			return err
 		}
 
-		if p.nulSepOutput {
-			removeLastEOL(tempBuffer)
+		if buffering {
+			if !binaryFormat {
+				// binary payloads have no trailing EOL to strip, and may
+				// legitimately end in a 0x0a/0x0d byte that isn't one.
+				removeLastEOL(tempBuffer)
+			}
 			tempBufferBytes := tempBuffer.Bytes()
-			if bytes.IndexByte(tempBufferBytes, 0) != -1 {
+			if p.nulSepOutput && bytes.IndexByte(tempBufferBytes, 0) != -1 {
 				return fmt.Errorf(
 					"Can't serialize value because it contains NUL char and you are using NUL separated output",
 				)
 			}
-			if _, err := writer.Write(tempBufferBytes); err != nil {
-				return err
+			if p.nulSepOutput && singleLineFormat && bytes.IndexByte(tempBufferBytes, '\n') != -1 {
+				return fmt.Errorf(
+					"Can't serialize value because it contains a newline char and you are using NUL separated %v output",
+					NDJSONOutputFormat.FormalName,
+				)
 			}
-			if _, err := writer.Write([]byte{0}); err != nil {
-				return err
+
+			if effectiveFraming != NoFraming {
+				framedBytes, err := frameBytes(effectiveFraming, tempBufferBytes)
+				if err != nil {
+					return err
+				}
+				if _, err := writer.Write(framedBytes); err != nil {
+					return err
+				}
+			} else {
+				if _, err := writer.Write(tempBufferBytes); err != nil {
+					return err
+				}
+			}
+
+			if p.nulSepOutput {
+				if _, err := writer.Write([]byte{0}); err != nil {
+					return err
+				}
 			}
 		}
 
 		p.previousDocIndex = mappedDoc.GetDocument()
+		p.previousFileIndex = mappedDoc.GetFileIndex()
 		if err := writer.Flush(); err != nil {
 			return err
 		}
 		log.Debugf("done printing results")
 	}
 
+	return p.printAppendix()
+}
+
+// printAppendix pipes any appendix reader (e.g. a jekyll front-matter body)
+// straight through to the writer once the matching nodes are printed.
+func (p *resultsPrinter) printAppendix() error {
 	// what happens if I remove output format check?
 	if p.appendixReader != nil {
 		writer, err := p.printerWriter.GetWriter(nil)
@@ -249,3 +434,163 @@ func (p *resultsPrinter) PrintResults(matchingNodes *list.List) error {
 
 	return nil
 }
+
+// printResultsConcurrently fans the CPU-bound encoding step for each
+// document out to a worker pool, where each worker owns its own Encoder from
+// p.encoderFactory and encodes into a private buffer. The calling goroutine
+// collects every result from the results channel, puts them back in original
+// order by index, and then writes the buffers to the PrinterWriter. Document separators are written straight to the
+// writer, outside of any frame, matching the sequential path so framing
+// (netstring/length-prefix) and NUL semantics agree between the two.
+func (p *resultsPrinter) printResultsConcurrently(matchingNodes *list.List, effectiveFraming FramingMode) error {
+	type printJob struct {
+		index          int
+		mappedDoc      *CandidateNode
+		needsSeparator bool
+	}
+
+	type printJobResult struct {
+		index          int
+		mappedDoc      *CandidateNode
+		needsSeparator bool
+		encoded        []byte
+		err            error
+	}
+
+	commentsStartWithSepExp := regexp.MustCompile(`^\$yqDocSeparator\$`)
+	singleLineFormat := p.formatCapabilities != nil && p.formatCapabilities.SingleLine
+	binaryFormat := p.formatCapabilities != nil && p.formatCapabilities.Binary
+	skipSeparator := singleLineFormat || binaryFormat
+	buffering := p.nulSepOutput || effectiveFraming != NoFraming
+
+	jobs := make([]printJob, 0, matchingNodes.Len())
+	for el := matchingNodes.Front(); el != nil; el = el.Next() {
+		mappedDoc := el.Value.(*CandidateNode)
+		commentStartsWithSeparator := commentsStartWithSepExp.MatchString(mappedDoc.LeadingContent)
+		needsSeparator := (p.previousDocIndex != mappedDoc.GetDocument() || p.previousFileIndex != mappedDoc.GetFileIndex()) && !commentStartsWithSeparator && !skipSeparator
+
+		p.previousDocIndex = mappedDoc.GetDocument()
+		p.previousFileIndex = mappedDoc.GetFileIndex()
+
+		jobs = append(jobs, printJob{index: len(jobs), mappedDoc: mappedDoc, needsSeparator: needsSeparator})
+	}
+
+	jobsCh := make(chan printJob)
+	resultsCh := make(chan printJobResult, len(jobs))
+
+	workerCount := p.concurrency
+	if workerCount > len(jobs) {
+		workerCount = len(jobs)
+	}
+
+	var workers sync.WaitGroup
+	for i := 0; i < workerCount; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			encoder := p.encoderFactory()
+			for job := range jobsCh {
+				// The document separator is never written into this buffer:
+				// it must land on the writer raw, outside any frame, exactly
+				// like the sequential path - otherwise a length prefix would
+				// end up covering the separator bytes too.
+				buffer := bytes.NewBuffer(nil)
+
+				if err := encoder.PrintLeadingContent(buffer, job.mappedDoc.LeadingContent); err != nil {
+					resultsCh <- printJobResult{index: job.index, err: err}
+					continue
+				}
+				if err := encoder.Encode(buffer, job.mappedDoc); err != nil {
+					resultsCh <- printJobResult{index: job.index, err: err}
+					continue
+				}
+
+				if buffering && !binaryFormat {
+					// binary payloads have no trailing EOL to strip, and may
+					// legitimately end in a 0x0a/0x0d byte that isn't one.
+					removeLastEOL(buffer)
+				}
+				resultsCh <- printJobResult{
+					index:          job.index,
+					mappedDoc:      job.mappedDoc,
+					needsSeparator: job.needsSeparator,
+					encoded:        buffer.Bytes(),
+				}
+			}
+		}()
+	}
+
+	go func() {
+		for _, job := range jobs {
+			jobsCh <- job
+		}
+		close(jobsCh)
+	}()
+
+	go func() {
+		workers.Wait()
+		close(resultsCh)
+	}()
+
+	ordered := make([]printJobResult, len(jobs))
+	for result := range resultsCh {
+		ordered[result.index] = result
+	}
+
+	for _, result := range ordered {
+		if result.err != nil {
+			return result.err
+		}
+
+		p.printedMatches = p.printedMatches || (result.mappedDoc.Tag != "!!null" &&
+			(result.mappedDoc.Tag != "!!bool" || result.mappedDoc.Value != "false"))
+
+		writer, err := p.printerWriter.GetWriter(result.mappedDoc)
+		if err != nil {
+			return err
+		}
+
+		if result.needsSeparator {
+			if err := p.encoder.PrintDocumentSeparator(writer); err != nil {
+				return err
+			}
+		}
+
+		encoded := result.encoded
+		if p.nulSepOutput && bytes.IndexByte(encoded, 0) != -1 {
+			return fmt.Errorf(
+				"Can't serialize value because it contains NUL char and you are using NUL separated output",
+			)
+		}
+		if p.nulSepOutput && singleLineFormat && bytes.IndexByte(encoded, '\n') != -1 {
+			return fmt.Errorf(
+				"Can't serialize value because it contains a newline char and you are using NUL separated %v output",
+				NDJSONOutputFormat.FormalName,
+			)
+		}
+
+		if effectiveFraming != NoFraming {
+			framedBytes, err := frameBytes(effectiveFraming, encoded)
+			if err != nil {
+				return err
+			}
+			encoded = framedBytes
+		}
+
+		if _, err := writer.Write(encoded); err != nil {
+			return err
+		}
+
+		if p.nulSepOutput {
+			if _, err := writer.Write([]byte{0}); err != nil {
+				return err
+			}
+		}
+
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package yqlib
+
+import (
+	"bufio"
+	"bytes"
+	"container/list"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeEncoder is a minimal Encoder test double: it writes a fixed payload
+// per CandidateNode.Value (looked up by value rather than call order, so it
+// stays correct when workers process jobs out of order) and a fixed
+// separator, without depending on any real codec.
+type fakeEncoder struct {
+	payloads         map[string][]byte
+	canHandleAliases bool
+}
+
+func (f *fakeEncoder) CanHandleAliases() bool {
+	return f.canHandleAliases
+}
+
+func (f *fakeEncoder) PrintDocumentSeparator(writer io.Writer) error {
+	_, err := writer.Write([]byte("---\n"))
+	return err
+}
+
+func (f *fakeEncoder) PrintLeadingContent(writer io.Writer, content string) error {
+	return nil
+}
+
+func (f *fakeEncoder) Encode(writer io.Writer, node *CandidateNode) error {
+	_, err := writer.Write(f.payloads[node.Value])
+	return err
+}
+
+// testPrinterWriter hands every document to the same underlying buffer,
+// mirroring the repo's single-stream PrinterWriter.
+type testPrinterWriter struct {
+	buffer *bytes.Buffer
+	writer *bufio.Writer
+}
+
+func newTestPrinterWriter() *testPrinterWriter {
+	buffer := &bytes.Buffer{}
+	return &testPrinterWriter{buffer: buffer, writer: bufio.NewWriter(buffer)}
+}
+
+func (t *testPrinterWriter) GetWriter(node *CandidateNode) (*bufio.Writer, error) {
+	return t.writer, nil
+}
+
+// newCandidateNode builds a CandidateNode for a given (document, file) pair.
+// document/fileIndex are unexported, so they're only reachable through
+// SetDocument/SetFileIndex.
+func newCandidateNode(value string, document uint, fileIndex int) *CandidateNode {
+	node := &CandidateNode{Value: value}
+	node.SetDocument(document)
+	node.SetFileIndex(fileIndex)
+	return node
+}
+
+func candidateNodeList(nodes ...*CandidateNode) *list.List {
+	results := list.New()
+	for _, node := range nodes {
+		results.PushBack(node)
+	}
+	return results
+}
+
+func TestPrintResultsConcurrentPreservesDocumentOrder(t *testing.T) {
+	payloads := map[string][]byte{}
+	nodes := make([]*CandidateNode, 0, 20)
+	for i := 0; i < 20; i++ {
+		value := fmt.Sprintf("%d", i)
+		payloads[value] = []byte(fmt.Sprintf("doc%d\n", i))
+		nodes = append(nodes, &CandidateNode{Value: value})
+	}
+
+	writer := newTestPrinterWriter()
+	printer := NewPrinter(&fakeEncoder{payloads: payloads, canHandleAliases: true}, writer)
+	printer.SetFormatCapabilities(FormatCapabilities{Aliases: true})
+	printer.SetEncoderFactory(func() Encoder { return &fakeEncoder{payloads: payloads, canHandleAliases: true} })
+	printer.SetConcurrency(4)
+
+	err := printer.PrintResults(candidateNodeList(nodes...))
+	assert.NoError(t, err)
+
+	expected := ""
+	for i := 0; i < 20; i++ {
+		expected += fmt.Sprintf("doc%d\n", i)
+	}
+	assert.Equal(t, expected, writer.buffer.String())
+}
+
+func TestPrintResultsSequentialAndConcurrentAgreeOnSeparators(t *testing.T) {
+	payloads := map[string][]byte{
+		"a": []byte("a\n"),
+		"b": []byte("b\n"),
+		"c": []byte("c\n"),
+	}
+	newNodes := func() []*CandidateNode {
+		return []*CandidateNode{
+			newCandidateNode("a", 0, 0),
+			newCandidateNode("b", 1, 0),
+			newCandidateNode("c", 0, 1),
+		}
+	}
+
+	sequentialWriter := newTestPrinterWriter()
+	sequentialPrinter := NewPrinter(&fakeEncoder{payloads: payloads, canHandleAliases: true}, sequentialWriter)
+	sequentialPrinter.SetFormatCapabilities(FormatCapabilities{Aliases: true})
+	assert.NoError(t, sequentialPrinter.PrintResults(candidateNodeList(newNodes()...)))
+
+	concurrentWriter := newTestPrinterWriter()
+	concurrentPrinter := NewPrinter(&fakeEncoder{payloads: payloads, canHandleAliases: true}, concurrentWriter)
+	concurrentPrinter.SetFormatCapabilities(FormatCapabilities{Aliases: true})
+	concurrentPrinter.SetEncoderFactory(func() Encoder { return &fakeEncoder{payloads: payloads, canHandleAliases: true} })
+	concurrentPrinter.SetConcurrency(4)
+	assert.NoError(t, concurrentPrinter.PrintResults(candidateNodeList(newNodes()...)))
+
+	assert.Equal(t, sequentialWriter.buffer.String(), concurrentWriter.buffer.String())
+	// each node after the first is in a different (document, file) pair from
+	// the node before it, so each of them - including the last, which changes
+	// both document and file - gets a separator.
+	assert.Equal(t, "a\n---\nb\n---\nc\n", sequentialWriter.buffer.String())
+}
+
+func TestPrintResultsFramingWithSeparatorsSequentialAndConcurrentAgree(t *testing.T) {
+	payloads := map[string][]byte{
+		"a": []byte("a\n"),
+		"b": []byte("b\n"),
+	}
+	newNodes := func() []*CandidateNode {
+		return []*CandidateNode{
+			newCandidateNode("a", 0, 0),
+			newCandidateNode("b", 1, 0),
+		}
+	}
+
+	sequentialWriter := newTestPrinterWriter()
+	sequentialPrinter := NewPrinter(&fakeEncoder{payloads: payloads, canHandleAliases: true}, sequentialWriter)
+	sequentialPrinter.SetFormatCapabilities(FormatCapabilities{Aliases: true})
+	sequentialPrinter.SetFraming(NetstringFraming)
+	assert.NoError(t, sequentialPrinter.PrintResults(candidateNodeList(newNodes()...)))
+
+	concurrentWriter := newTestPrinterWriter()
+	concurrentPrinter := NewPrinter(&fakeEncoder{payloads: payloads, canHandleAliases: true}, concurrentWriter)
+	concurrentPrinter.SetFormatCapabilities(FormatCapabilities{Aliases: true})
+	concurrentPrinter.SetFraming(NetstringFraming)
+	concurrentPrinter.SetEncoderFactory(func() Encoder { return &fakeEncoder{payloads: payloads, canHandleAliases: true} })
+	concurrentPrinter.SetConcurrency(4)
+	assert.NoError(t, concurrentPrinter.PrintResults(candidateNodeList(newNodes()...)))
+
+	assert.Equal(t, sequentialWriter.buffer.String(), concurrentWriter.buffer.String())
+	// the separator sits raw between the two netstring frames, never inside
+	// one - a length prefix covering "---\n" too would corrupt framing for
+	// any reader decoding the stream frame-by-frame. Each payload's trailing
+	// newline is stripped before it is framed.
+	assert.Equal(t, "1:a,---\n1:b,", sequentialWriter.buffer.String())
+}
+
+func binaryFramingNodesAndPayloads() ([]*CandidateNode, map[string][]byte) {
+	payloads := map[string][]byte{
+		"first":  {0x01, 0x0a},
+		"second": {0x02, 0x0d},
+	}
+	nodes := []*CandidateNode{
+		{Value: "first"},
+		{Value: "second"},
+	}
+	return nodes, payloads
+}
+
+func TestPrintResultsBinaryFramingKeepsTrailingControlBytesSequential(t *testing.T) {
+	nodes, payloads := binaryFramingNodesAndPayloads()
+
+	writer := newTestPrinterWriter()
+	printer := NewPrinter(&fakeEncoder{payloads: payloads, canHandleAliases: true}, writer)
+	printer.SetFormatCapabilities(FormatCapabilities{Binary: true, Aliases: true})
+
+	err := printer.PrintResults(candidateNodeList(nodes...))
+	assert.NoError(t, err)
+
+	expected := []byte{0, 0, 0, 2, 0x01, 0x0a, 0, 0, 0, 2, 0x02, 0x0d}
+	assert.Equal(t, expected, writer.buffer.Bytes())
+}
+
+func TestPrintResultsBinaryFramingKeepsTrailingControlBytesConcurrent(t *testing.T) {
+	nodes, payloads := binaryFramingNodesAndPayloads()
+
+	writer := newTestPrinterWriter()
+	printer := NewPrinter(&fakeEncoder{payloads: payloads, canHandleAliases: true}, writer)
+	printer.SetFormatCapabilities(FormatCapabilities{Binary: true, Aliases: true})
+	printer.SetEncoderFactory(func() Encoder { return &fakeEncoder{payloads: payloads, canHandleAliases: true} })
+	printer.SetConcurrency(4)
+
+	err := printer.PrintResults(candidateNodeList(nodes...))
+	assert.NoError(t, err)
+
+	expected := []byte{0, 0, 0, 2, 0x01, 0x0a, 0, 0, 0, 2, 0x02, 0x0d}
+	assert.Equal(t, expected, writer.buffer.Bytes())
+}